@@ -0,0 +1,794 @@
+package integrations
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redhat-appstudio/tssc-cli/pkg/config"
+	"github.com/redhat-appstudio/tssc-cli/pkg/k8s"
+	"github.com/redhat-appstudio/tssc-cli/pkg/retry"
+	"github.com/spf13/cobra"
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// defaultPublicGitLabHost is the default host for public GitLab.
+const defaultPublicGitLabHost = "gitlab.com"
+
+// oauthApplicationScopes are the scopes required by TSSC on the GitLab OAuth
+// application it provisions for itself.
+const oauthApplicationScopes = "api read_user openid"
+
+// oauthGroupOwnerAccessLevel is the GitLab access level ("Owner") required on
+// --group for the OAuth application to be provisioned group-scoped.
+const oauthGroupOwnerAccessLevel = 50
+
+// GitLabIntegration represents the TSSC GitLab integration.
+type GitLabIntegration struct {
+	logger *slog.Logger // application logger
+	kube   *k8s.Kube    // kubernetes client
+
+	force    bool // overwrite the existing secret
+	insecure bool // Skips tls verification on api calls
+
+	host         string // GitLab host
+	clientId     string // GitLab application client id
+	clientSecret string // GitLab application client secret
+	token        string // API token credentials
+	group        string // GitLab group name
+
+	caCert     string // CA certificate bundle, path or inline PEM
+	clientCert string // client certificate for mTLS, path or inline PEM
+	clientKey  string // client private key for mTLS, path or inline PEM
+
+	apiRetries      int           // max attempts for transient API failures
+	apiRetryMaxWait time.Duration // backoff ceiling between retries
+
+	// oauthAutoProvisioned records whether ensureOAuthApplication created or
+	// rotated the GitLab OAuth application itself, as opposed to the caller
+	// supplying an existing one via --app-id/--app-secret. store persists
+	// this alongside the secret, and Delete only deletes the application by
+	// name when it is set, so it never removes an application it didn't
+	// provision.
+	oauthAutoProvisioned bool
+}
+
+// PersistentFlags sets the persistent flags for the GitLab integration.
+func (g *GitLabIntegration) PersistentFlags(c *cobra.Command) {
+	p := c.PersistentFlags()
+
+	p.BoolVar(&g.force, "force", g.force,
+		"Overwrite the existing secret")
+	p.BoolVar(&g.insecure, "insecure", g.insecure,
+		"Skips tls verification on api calls")
+
+	p.StringVar(&g.host, "host", g.host,
+		"GitLab host, defaults to 'gitlab.com'")
+	p.StringVar(&g.clientId, "app-id", g.clientId,
+		"GitLab application client id")
+	p.StringVar(&g.clientSecret, "app-secret", g.clientSecret,
+		"GitLab application client secret")
+	p.StringVar(&g.token, "token", g.token,
+		"GitLab API token")
+	p.StringVar(&g.group, "group", g.group,
+		"GitLab group name")
+
+	p.StringVar(&g.caCert, "ca-cert", g.caCert,
+		"CA certificate bundle used to verify the GitLab API, path or inline PEM")
+	p.StringVar(&g.clientCert, "client-cert", g.clientCert,
+		"Client certificate for mTLS authentication with the GitLab API, path or inline PEM")
+	p.StringVar(&g.clientKey, "client-key", g.clientKey,
+		"Client private key for mTLS authentication with the GitLab API, path or inline PEM")
+
+	p.IntVar(&g.apiRetries, "api-retries", g.apiRetries,
+		"Max attempts for transient GitLab/Kubernetes API failures")
+	p.DurationVar(&g.apiRetryMaxWait, "api-retry-max-wait", g.apiRetryMaxWait,
+		"Backoff ceiling between API retries")
+
+	for _, f := range []string{"token", "group"} {
+		if err := c.MarkPersistentFlagRequired(f); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// log logger with contextual information.
+func (g *GitLabIntegration) log() *slog.Logger {
+	return g.logger.With(
+		"force", g.force,
+		"insecure", g.insecure,
+		"host", g.host,
+		"clientId", g.clientId,
+		"clientSecret-len", len(g.clientSecret),
+		"token-len", len(g.token),
+		"group", g.group,
+		"caCert-len", len(g.caCert),
+		"clientCert-len", len(g.clientCert),
+		"clientKey-len", len(g.clientKey),
+		"apiRetries", g.apiRetries,
+		"apiRetryMaxWait", g.apiRetryMaxWait,
+	)
+}
+
+// Validate checks if the required configuration is set.
+func (g *GitLabIntegration) Validate() error {
+	if g.clientId != "" && g.clientSecret == "" {
+		return fmt.Errorf("app-secret is required when id is specified")
+	}
+	if g.clientId == "" && g.clientSecret != "" {
+		return fmt.Errorf("app-id is required when app-secret is specified")
+	}
+	if (g.clientCert == "") != (g.clientKey == "") {
+		return fmt.Errorf("client-cert and client-key must be specified together")
+	}
+	if g.apiRetries < 1 {
+		return fmt.Errorf("api-retries must be at least 1")
+	}
+	return nil
+}
+
+// EnsureNamespace ensures the namespace needed for the GitLab integration secret
+// is created on the cluster.
+func (g *GitLabIntegration) EnsureNamespace(
+	ctx context.Context,
+	cfg *config.Config,
+) error {
+	return k8s.EnsureOpenShiftProject(
+		ctx,
+		g.log(),
+		g.kube,
+		cfg.Installer.Namespace,
+	)
+}
+
+// gitlabSecretName returns the name of the GitLab integration secret,
+// shared by GitLabIntegration.secretName and GitLabHTTPClientFromCluster,
+// whose caller doesn't have a GitLabIntegration instance to hand it from.
+func gitlabSecretName(cfg *config.Config) types.NamespacedName {
+	return types.NamespacedName{
+		Namespace: cfg.Installer.Namespace,
+		Name:      "tssc-gitlab-integration",
+	}
+}
+
+// secretName returns the secret name for the integration. The name is "lazy"
+// generated to make sure configuration is already loaded.
+func (g *GitLabIntegration) secretName(cfg *config.Config) types.NamespacedName {
+	return gitlabSecretName(cfg)
+}
+
+// prepareSecret checks if the secret already exists, and if so, it will delete
+// the secret if the force flag is enabled.
+func (g *GitLabIntegration) prepareSecret(
+	ctx context.Context,
+	cfg *config.Config,
+) error {
+	g.log().Debug("Checking if integration secret exists")
+	exists, err := k8s.SecretExists(ctx, g.kube, g.secretName(cfg))
+	if err != nil {
+		return err
+	}
+	if !exists {
+		g.log().Debug("Integration secret does not exist")
+		return nil
+	}
+	if !g.force {
+		g.log().Debug("Integration secret already exists")
+		return fmt.Errorf("%w: %s",
+			ErrSecretAlreadyExists, g.secretName(cfg).String())
+	}
+	g.log().Debug("Integration secret already exists, recreating it")
+	return retry.Do(ctx, g.retryConfig(), func(ctx context.Context) error {
+		return k8s.DeleteSecret(ctx, g.kube, g.secretName(cfg))
+	})
+}
+
+// retryConfig returns the backoff configuration for transient GitLab and
+// Kubernetes API failures, tuned by --api-retries and --api-retry-max-wait.
+func (g *GitLabIntegration) retryConfig() retry.Config {
+	cfg := retry.DefaultConfig
+	cfg.MaxAttempts = g.apiRetries
+	cfg.MaxWait = g.apiRetryMaxWait
+	return cfg
+}
+
+// loadPEM returns the PEM-encoded contents referenced by value, which may be
+// either an inline PEM block or a filesystem path.
+func loadPEM(value string) ([]byte, error) {
+	if strings.Contains(value, "-----BEGIN") {
+		return []byte(value), nil
+	}
+	return os.ReadFile(value)
+}
+
+// tlsConfigFromPEM assembles a tls.Config combining the system trust store
+// with an optional CA bundle, and loading a client certificate when both
+// clientCert and clientKey are set. caCert, clientCert and clientKey may each
+// be either an inline PEM block or a filesystem path (see loadPEM). It backs
+// every integration that talks TLS to an external API (GitLab, the image
+// registry probe) so none of them re-implement this construction.
+func tlsConfigFromPEM(caCert, clientCert, clientKey string, insecure bool) (*tls.Config, error) {
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+	if caCert != "" {
+		caPEM, err := loadPEM(caCert)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load CA certificate: %w", err)
+		}
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("failed to parse CA certificate %q", caCert)
+		}
+	}
+
+	tlsConfig := &tls.Config{
+		RootCAs:            pool,
+		InsecureSkipVerify: insecure, //nolint:gosec
+		MinVersion:         tls.VersionTLS12,
+	}
+
+	if clientCert != "" && clientKey != "" {
+		certPEM, err := loadPEM(clientCert)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		keyPEM, err := loadPEM(clientKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client key: %w", err)
+		}
+		cert, err := tls.X509KeyPair(certPEM, keyPEM)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
+// tlsConfig assembles the tls.Config used for GitLab API calls, combining the
+// system trust store with the optional CA bundle informed via --ca-cert, and
+// loading the client certificate when both --client-cert and --client-key are
+// set.
+func (g *GitLabIntegration) tlsConfig() (*tls.Config, error) {
+	return tlsConfigFromPEM(g.caCert, g.clientCert, g.clientKey, g.insecure)
+}
+
+// HTTPClient builds the *http.Client used for GitLab API calls, configured
+// with the CA bundle and optional client certificate informed via --ca-cert,
+// --client-cert and --client-key. Every call builds a new client.
+func (g *GitLabIntegration) HTTPClient() (*http.Client, error) {
+	tlsConfig, err := g.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+	return &http.Client{
+		Transport: &http.Transport{TLSClientConfig: tlsConfig},
+	}, nil
+}
+
+// HTTPClientFromSecretData builds an *http.Client configured with the CA
+// bundle and optional client certificate stored in a GitLab integration
+// secret (see GitLabIntegration.store), so callers that need to reach GitLab
+// (e.g. deploy jobs cloning a repository or delivering webhooks) can reuse the
+// same TLS configuration without re-implementing it.
+func HTTPClientFromSecretData(data map[string][]byte, insecure bool) (*http.Client, error) {
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+	if caCert := data["caCert"]; len(caCert) > 0 {
+		if !pool.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("failed to parse CA certificate from secret")
+		}
+	}
+
+	tlsConfig := &tls.Config{
+		RootCAs:            pool,
+		InsecureSkipVerify: insecure, //nolint:gosec
+		MinVersion:         tls.VersionTLS12,
+	}
+
+	tlsCert, hasCert := data["tlsCert"]
+	tlsKey, hasKey := data["tlsKey"]
+	switch {
+	case len(tlsCert) > 0 && len(tlsKey) > 0:
+		cert, err := tls.X509KeyPair(tlsCert, tlsKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build client certificate from secret: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	case hasCert != hasKey:
+		return nil, fmt.Errorf("secret must contain both tlsCert and tlsKey")
+	}
+
+	return &http.Client{
+		Transport: &http.Transport{TLSClientConfig: tlsConfig},
+	}, nil
+}
+
+// GitLabHTTPClientFromCluster reads the GitLab integration secret straight
+// from the cluster and builds an *http.Client from it via
+// HTTPClientFromSecretData, for callers that only have cluster access and the
+// installer namespace (e.g. a deploy Job cloning a repository or delivering a
+// webhook) and were never handed the --ca-cert/--client-cert/--client-key
+// flags used when the integration was created.
+func GitLabHTTPClientFromCluster(
+	ctx context.Context,
+	kube *k8s.Kube,
+	cfg *config.Config,
+	insecure bool,
+) (*http.Client, error) {
+	secretName := gitlabSecretName(cfg)
+	coreClient, err := kube.CoreV1ClientSet(secretName.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := coreClient.Secrets(secretName.Namespace).
+		Get(ctx, secretName.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read GitLab integration secret: %w", err)
+	}
+	return HTTPClientFromSecretData(secret.Data, insecure)
+}
+
+// getCurrentGitLabUser gets the current user name authenticated with access
+// token, retrying transient failures with backoff and honoring the
+// "Retry-After" header when GitLab reports rate limiting.
+func (g *GitLabIntegration) getCurrentGitLabUser(ctx context.Context) (string, error) {
+	baseURL := fmt.Sprintf("https://%s", g.host)
+	logger := g.log()
+
+	hcl, err := g.HTTPClient()
+	if err != nil {
+		logger.Error("Error building TLS client")
+		return "", err
+	}
+
+	cl, err := gitlab.NewClient(
+		g.token, gitlab.WithBaseURL(baseURL), gitlab.WithHTTPClient(hcl))
+	if err != nil {
+		logger.Error("Error building gitlab client")
+		return "", err
+	}
+
+	var username string
+	err = retry.Do(ctx, g.retryConfig(), func(_ context.Context) error {
+		user, resp, err := cl.Users.CurrentUser()
+		if err != nil {
+			if resp != nil && resp.StatusCode >= 400 {
+				statusErr := &retry.HTTPStatusError{
+					StatusCode: resp.StatusCode,
+					Status:     err.Error(),
+				}
+				if wait := retryAfterDuration(resp.Header.Get("Retry-After")); wait > 0 {
+					return retry.After(statusErr, wait)
+				}
+				return statusErr
+			}
+			return err
+		}
+		username = user.Username
+		return nil
+	})
+	if err != nil {
+		logger.Error("Error getting user")
+		return "", err
+	}
+
+	return username, nil
+}
+
+// retryAfterDuration parses the number of seconds in an HTTP "Retry-After"
+// header, returning 0 when absent or malformed.
+func retryAfterDuration(value string) time.Duration {
+	seconds, err := strconv.Atoi(value)
+	if err != nil || seconds <= 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// store creates the secret with the integration data.
+func (g *GitLabIntegration) store(
+	ctx context.Context,
+	cfg *config.Config,
+) error {
+	// Getting the user name
+	username, err := g.getCurrentGitLabUser(ctx)
+	if err != nil {
+		return err
+	}
+
+	data := map[string][]byte{
+		"clientId":             []byte(g.clientId),
+		"clientSecret":         []byte(g.clientSecret),
+		"host":                 []byte(g.host),
+		"token":                []byte(g.token),
+		"group":                []byte(g.group),
+		"username":             []byte(username),
+		"oauthAutoProvisioned": []byte(strconv.FormatBool(g.oauthAutoProvisioned)),
+	}
+	if g.caCert != "" {
+		caPEM, err := loadPEM(g.caCert)
+		if err != nil {
+			return fmt.Errorf("failed to load CA certificate: %w", err)
+		}
+		data["caCert"] = caPEM
+	}
+	if g.clientCert != "" && g.clientKey != "" {
+		certPEM, err := loadPEM(g.clientCert)
+		if err != nil {
+			return fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		keyPEM, err := loadPEM(g.clientKey)
+		if err != nil {
+			return fmt.Errorf("failed to load client key: %w", err)
+		}
+		data["tlsCert"] = certPEM
+		data["tlsKey"] = keyPEM
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: g.secretName(cfg).Namespace,
+			Name:      g.secretName(cfg).Name,
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: data,
+	}
+	logger := g.log().With(
+		"secret-namespace", secret.GetNamespace(),
+		"secret-name", secret.GetName(),
+	)
+
+	logger.Debug("Creating integration secret")
+	coreClient, err := g.kube.CoreV1ClientSet(g.secretName(cfg).Namespace)
+	if err != nil {
+		return err
+	}
+	err = retry.Do(ctx, g.retryConfig(), func(ctx context.Context) error {
+		_, err := coreClient.Secrets(g.secretName(cfg).Namespace).
+			Create(ctx, secret, metav1.CreateOptions{})
+		return err
+	})
+	if err == nil {
+		logger.Info("Integration secret created successfully!")
+	}
+	return err
+}
+
+// Create creates the GitLab integration Kubernetes secret.
+func (g *GitLabIntegration) Create(
+	ctx context.Context,
+	cfg *config.Config,
+) error {
+	logger := g.log()
+	logger.Info("Inspecting the cluster for an existing GitLab integration secret")
+	if err := g.prepareSecret(ctx, cfg); err != nil {
+		return err
+	}
+	if err := g.ensureOAuthApplication(ctx, cfg); err != nil {
+		return err
+	}
+	return g.store(ctx, cfg)
+}
+
+// Delete removes the GitLab OAuth application provisioned by
+// ensureOAuthApplication for this cluster, if any, so uninstalling TSSC does
+// not leave an orphaned application registered on GitLab. It only does so
+// when the stored integration secret says the application was auto-
+// provisioned by TSSC; an application supplied via --app-id/--app-secret is
+// left alone, even if it happens to be named like TSSC's own convention.
+func (g *GitLabIntegration) Delete(ctx context.Context, cfg *config.Config) error {
+	logger := g.log()
+	autoProvisioned, err := g.wasOAuthAutoProvisioned(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	if !autoProvisioned {
+		logger.Debug("GitLab OAuth application was not auto-provisioned by TSSC, leaving it in place")
+		return nil
+	}
+	groupScoped := g.isGroupOwner(ctx)
+	app, err := g.findOAuthApplication(ctx, oauthApplicationName(cfg), groupScoped)
+	if err != nil {
+		return fmt.Errorf("failed to look up OAuth application: %w", err)
+	}
+	if app == nil {
+		logger.Debug("No GitLab OAuth application to remove")
+		return nil
+	}
+	logger.Debug("Removing GitLab OAuth application", "application-id", app.ID)
+	return g.gitlabAPIRequest(
+		ctx, http.MethodDelete, g.oauthApplicationPath(groupScoped, app.ID), nil, nil)
+}
+
+// wasOAuthAutoProvisioned reports whether the integration secret stored by a
+// previous Create recorded that this tool provisioned the GitLab OAuth
+// application itself, rather than reusing one supplied via
+// --app-id/--app-secret. A missing secret, or a secret predating this field,
+// is treated as "no", so Delete never deletes an application it can't prove
+// it created.
+func (g *GitLabIntegration) wasOAuthAutoProvisioned(ctx context.Context, cfg *config.Config) (bool, error) {
+	secretName := g.secretName(cfg)
+	coreClient, err := g.kube.CoreV1ClientSet(secretName.Namespace)
+	if err != nil {
+		return false, err
+	}
+	secret, err := coreClient.Secrets(secretName.Namespace).
+		Get(ctx, secretName.Name, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to read integration secret: %w", err)
+	}
+	autoProvisioned, _ := strconv.ParseBool(string(secret.Data["oauthAutoProvisioned"]))
+	return autoProvisioned, nil
+}
+
+// oauthApplicationName returns the deterministic GitLab OAuth application
+// name used to find and rotate credentials across re-runs.
+func oauthApplicationName(cfg *config.Config) string {
+	return fmt.Sprintf("tssc-%s", cfg.Installer.IngressDomain)
+}
+
+// oauthRedirectURI returns the OAuth redirect URI the application should be
+// registered with, derived from the cluster's ingress domain.
+func oauthRedirectURI(cfg *config.Config) string {
+	return fmt.Sprintf("https://%s/oauth/callback", cfg.Installer.IngressDomain)
+}
+
+// gitlabApplication is the subset of the GitLab "application" resource TSSC
+// cares about when provisioning its own OAuth application.
+type gitlabApplication struct {
+	ID            int    `json:"id"`
+	ApplicationID string `json:"application_id"`
+	Secret        string `json:"secret"`
+	Name          string `json:"name"`
+}
+
+// gitlabAPIRequest performs an authenticated request against the GitLab REST
+// API, honoring the same TLS configuration as the SDK-backed calls, and
+// decodes the JSON response into out when informed.
+func (g *GitLabIntegration) gitlabAPIRequest(
+	ctx context.Context,
+	method string,
+	path string,
+	body any,
+	out any,
+) error {
+	hcl, err := g.HTTPClient()
+	if err != nil {
+		return err
+	}
+
+	var reqBody io.Reader
+	if body != nil {
+		raw, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode request body: %w", err)
+		}
+		reqBody = bytes.NewReader(raw)
+	}
+
+	req, err := http.NewRequestWithContext(
+		ctx, method, fmt.Sprintf("https://%s%s", g.host, path), reqBody)
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("PRIVATE-TOKEN", g.token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := hcl.Do(req)
+	if err != nil {
+		return fmt.Errorf("gitlab api request to %s failed: %w", path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		raw, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("gitlab api %s %s returned status %d: %s",
+			method, path, resp.StatusCode, string(raw))
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// isGroupOwner reports whether the authenticated user is an Owner of
+// --group, which decides whether the OAuth application is provisioned
+// group-scoped or falls back to an instance-scoped application.
+func (g *GitLabIntegration) isGroupOwner(ctx context.Context) bool {
+	var user struct {
+		ID int `json:"id"`
+	}
+	if err := g.gitlabAPIRequest(ctx, http.MethodGet, "/api/v4/user", nil, &user); err != nil {
+		return false
+	}
+
+	var member struct {
+		AccessLevel int `json:"access_level"`
+	}
+	path := fmt.Sprintf(
+		"/api/v4/groups/%s/members/%d", url.PathEscape(g.group), user.ID)
+	if err := g.gitlabAPIRequest(ctx, http.MethodGet, path, nil, &member); err != nil {
+		return false
+	}
+	return member.AccessLevel >= oauthGroupOwnerAccessLevel
+}
+
+// oauthApplicationsPath returns the GitLab REST endpoint used to list or
+// create OAuth applications. The Applications API does not accept a
+// "group_id" field on the instance-wide endpoint, so a group-scoped
+// application must go through the dedicated groups endpoint instead, which is
+// also the one a plain group-owner token (without instance-admin rights) is
+// allowed to call.
+func (g *GitLabIntegration) oauthApplicationsPath(groupScoped bool) string {
+	if groupScoped {
+		return fmt.Sprintf("/api/v4/groups/%s/applications", url.PathEscape(g.group))
+	}
+	return "/api/v4/applications"
+}
+
+// oauthApplicationPath returns the GitLab REST endpoint for a single existing
+// OAuth application, mirroring oauthApplicationsPath's group/instance split.
+func (g *GitLabIntegration) oauthApplicationPath(groupScoped bool, id int) string {
+	if groupScoped {
+		return fmt.Sprintf("%s/%d", g.oauthApplicationsPath(true), id)
+	}
+	return fmt.Sprintf("/api/v4/applications/%d", id)
+}
+
+// findOAuthApplication looks up an existing OAuth application by name among
+// the applications visible to the configured token, scoped to --group when
+// groupScoped is set.
+func (g *GitLabIntegration) findOAuthApplication(
+	ctx context.Context,
+	name string,
+	groupScoped bool,
+) (*gitlabApplication, error) {
+	var apps []gitlabApplication
+	if err := g.gitlabAPIRequest(
+		ctx, http.MethodGet, g.oauthApplicationsPath(groupScoped), nil, &apps,
+	); err != nil {
+		return nil, err
+	}
+	for i := range apps {
+		if apps[i].Name == name {
+			return &apps[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// createOAuthApplication creates the OAuth application TSSC uses to
+// authenticate with GitLab, scoping it to --group when the token owner is a
+// group Owner, falling back to an instance-scoped application otherwise.
+func (g *GitLabIntegration) createOAuthApplication(
+	ctx context.Context,
+	name string,
+	redirectURI string,
+	groupScoped bool,
+) (*gitlabApplication, error) {
+	body := map[string]string{
+		"name":         name,
+		"redirect_uri": redirectURI,
+		"scopes":       oauthApplicationScopes,
+	}
+
+	var app gitlabApplication
+	if err := g.gitlabAPIRequest(
+		ctx, http.MethodPost, g.oauthApplicationsPath(groupScoped), body, &app,
+	); err != nil {
+		return nil, err
+	}
+	return &app, nil
+}
+
+// rotateOAuthApplicationSecret rotates the secret of the OAuth application
+// identified by id, returning the application with its refreshed secret.
+func (g *GitLabIntegration) rotateOAuthApplicationSecret(
+	ctx context.Context,
+	id int,
+	groupScoped bool,
+) (*gitlabApplication, error) {
+	var app gitlabApplication
+	path := g.oauthApplicationPath(groupScoped, id)
+	if err := g.gitlabAPIRequest(ctx, http.MethodPut, path, nil, &app); err != nil {
+		return nil, err
+	}
+	return &app, nil
+}
+
+// ensureOAuthApplication provisions the GitLab OAuth application used by
+// TSSC when the caller did not provide --app-id/--app-secret, so a single CLI
+// invocation reaches steady state without a manual trip through the GitLab
+// UI to create the application. On --force it looks up the existing
+// application by name and rotates its secret instead of creating a
+// duplicate.
+func (g *GitLabIntegration) ensureOAuthApplication(
+	ctx context.Context,
+	cfg *config.Config,
+) error {
+	if g.clientId != "" || g.clientSecret != "" {
+		return nil
+	}
+
+	logger := g.log()
+	name := oauthApplicationName(cfg)
+	groupScoped := g.isGroupOwner(ctx)
+
+	if g.force {
+		existing, err := g.findOAuthApplication(ctx, name, groupScoped)
+		if err != nil {
+			return fmt.Errorf("failed to look up existing OAuth application: %w", err)
+		}
+		if existing != nil {
+			logger.Debug("Rotating existing GitLab OAuth application secret")
+			rotated, err := g.rotateOAuthApplicationSecret(ctx, existing.ID, groupScoped)
+			if err != nil {
+				return fmt.Errorf("failed to rotate OAuth application secret: %w", err)
+			}
+			g.clientId = rotated.ApplicationID
+			g.clientSecret = rotated.Secret
+			g.oauthAutoProvisioned = true
+			return nil
+		}
+	}
+
+	logger.Debug("Creating GitLab OAuth application")
+	app, err := g.createOAuthApplication(ctx, name, oauthRedirectURI(cfg), groupScoped)
+	if err != nil {
+		return fmt.Errorf("failed to create OAuth application: %w", err)
+	}
+	g.clientId = app.ApplicationID
+	g.clientSecret = app.Secret
+	g.oauthAutoProvisioned = true
+	return nil
+}
+
+func NewGitLabIntegration(
+	logger *slog.Logger,
+	kube *k8s.Kube,
+) *GitLabIntegration {
+	return &GitLabIntegration{
+		logger: logger,
+		kube:   kube,
+
+		force:        false,
+		insecure:     false,
+		host:         defaultPublicGitLabHost,
+		clientId:     "",
+		clientSecret: "",
+		token:        "",
+		group:        "",
+
+		caCert:     "",
+		clientCert: "",
+		clientKey:  "",
+
+		apiRetries:      retry.DefaultConfig.MaxAttempts,
+		apiRetryMaxWait: retry.DefaultConfig.MaxWait,
+	}
+}
@@ -0,0 +1,227 @@
+package integrations
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// generateTestCertPEM returns a self-signed certificate/key pair PEM-encoded,
+// good enough to exercise tlsConfigFromPEM's parsing without touching a real
+// CA or the filesystem.
+func generateTestCertPEM(t *testing.T) (certPEM, keyPEM string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "test"},
+		NotBefore:    time.Unix(0, 0),
+		NotAfter:     time.Unix(0, 0).Add(24 * time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("failed to marshal key: %v", err)
+	}
+
+	certPEM = string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+	keyPEM = string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}))
+	return certPEM, keyPEM
+}
+
+func TestTLSConfigFromPEM(t *testing.T) {
+	certPEM, keyPEM := generateTestCertPEM(t)
+
+	t.Run("no CA or client cert", func(t *testing.T) {
+		cfg, err := tlsConfigFromPEM("", "", "", false)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cfg.InsecureSkipVerify {
+			t.Errorf("InsecureSkipVerify = true; want false")
+		}
+		if len(cfg.Certificates) != 0 {
+			t.Errorf("Certificates = %v; want none", cfg.Certificates)
+		}
+	})
+
+	t.Run("insecure passthrough", func(t *testing.T) {
+		cfg, err := tlsConfigFromPEM("", "", "", true)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !cfg.InsecureSkipVerify {
+			t.Errorf("InsecureSkipVerify = false; want true")
+		}
+	})
+
+	t.Run("inline CA certificate", func(t *testing.T) {
+		cfg, err := tlsConfigFromPEM(certPEM, "", "", false)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cfg.RootCAs == nil {
+			t.Errorf("RootCAs was not populated with the inline CA certificate")
+		}
+	})
+
+	t.Run("invalid CA certificate", func(t *testing.T) {
+		if _, err := tlsConfigFromPEM("-----BEGIN CERTIFICATE-----\nbogus\n-----END CERTIFICATE-----", "", "", false); err == nil {
+			t.Fatal("expected an error for an unparseable CA certificate")
+		}
+	})
+
+	t.Run("inline client certificate and key", func(t *testing.T) {
+		cfg, err := tlsConfigFromPEM("", certPEM, keyPEM, false)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(cfg.Certificates) != 1 {
+			t.Fatalf("Certificates = %d entries; want 1", len(cfg.Certificates))
+		}
+	})
+
+	t.Run("client cert without a matching key is ignored", func(t *testing.T) {
+		cfg, err := tlsConfigFromPEM("", certPEM, "", false)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(cfg.Certificates) != 0 {
+			t.Errorf("Certificates = %d entries; want 0 when clientKey is unset", len(cfg.Certificates))
+		}
+	})
+}
+
+func TestOAuthApplicationsPath(t *testing.T) {
+	tests := []struct {
+		name        string
+		group       string
+		groupScoped bool
+		want        string
+	}{
+		{
+			name: "instance-wide",
+			want: "/api/v4/applications",
+		},
+		{
+			name:        "group-scoped",
+			group:       "my-group",
+			groupScoped: true,
+			want:        "/api/v4/groups/my-group/applications",
+		},
+		{
+			name:        "group-scoped name needing escaping",
+			group:       "my/sub-group",
+			groupScoped: true,
+			want:        "/api/v4/groups/my%2Fsub-group/applications",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := &GitLabIntegration{group: tt.group}
+			if got := g.oauthApplicationsPath(tt.groupScoped); got != tt.want {
+				t.Errorf("oauthApplicationsPath(%v) = %q; want %q", tt.groupScoped, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOAuthApplicationPath(t *testing.T) {
+	tests := []struct {
+		name        string
+		group       string
+		groupScoped bool
+		id          int
+		want        string
+	}{
+		{
+			name: "instance-wide",
+			id:   42,
+			want: "/api/v4/applications/42",
+		},
+		{
+			name:        "group-scoped",
+			group:       "my-group",
+			groupScoped: true,
+			id:          7,
+			want:        "/api/v4/groups/my-group/applications/7",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := &GitLabIntegration{group: tt.group}
+			if got := g.oauthApplicationPath(tt.groupScoped, tt.id); got != tt.want {
+				t.Errorf("oauthApplicationPath(%v, %d) = %q; want %q", tt.groupScoped, tt.id, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOAuthAutoProvisionedMarkerParsing(t *testing.T) {
+	// wasOAuthAutoProvisioned's own parsing of the stored marker is covered
+	// indirectly through strconv.ParseBool semantics: an absent or malformed
+	// value must read as "false" rather than erroring, so Delete's default is
+	// to leave the application alone. Exercise that directly against the
+	// underlying parse, since reaching the method itself requires a live
+	// *k8s.Kube this package's test build doesn't have access to.
+	for _, raw := range []string{"", "not-a-bool"} {
+		if v, _ := strconv.ParseBool(raw); v {
+			t.Errorf("strconv.ParseBool(%q) = true; want false", raw)
+		}
+	}
+}
+
+func TestHTTPClientFromSecretData(t *testing.T) {
+	certPEM, keyPEM := generateTestCertPEM(t)
+
+	t.Run("minimal secret", func(t *testing.T) {
+		client, err := HTTPClientFromSecretData(map[string][]byte{}, false)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if client == nil {
+			t.Fatal("expected a non-nil client")
+		}
+	})
+
+	t.Run("CA and client certificate from secret data", func(t *testing.T) {
+		data := map[string][]byte{
+			"caCert":  []byte(certPEM),
+			"tlsCert": []byte(certPEM),
+			"tlsKey":  []byte(keyPEM),
+		}
+		if _, err := HTTPClientFromSecretData(data, false); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("tlsCert without tlsKey is rejected", func(t *testing.T) {
+		data := map[string][]byte{"tlsCert": []byte(certPEM)}
+		if _, err := HTTPClientFromSecretData(data, false); err == nil {
+			t.Fatal("expected an error when only tlsCert is set")
+		}
+	})
+
+	t.Run("invalid CA certificate", func(t *testing.T) {
+		data := map[string][]byte{"caCert": []byte("not a certificate")}
+		if _, err := HTTPClientFromSecretData(data, false); err == nil {
+			t.Fatal("expected an error for an unparseable CA certificate")
+		}
+	})
+}
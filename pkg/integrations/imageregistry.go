@@ -0,0 +1,615 @@
+package integrations
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/redhat-appstudio/tssc-cli/pkg/config"
+	"github.com/redhat-appstudio/tssc-cli/pkg/k8s"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// registryProbeTimeout bounds each HTTP call made while probing a registry.
+const registryProbeTimeout = 10 * time.Second
+
+// defaultImagePullServiceAccounts are the ServiceAccounts that receive the
+// registry secret in the integration's own namespace, so pods launched by
+// TSSC components can pull private images out of the box.
+var defaultImagePullServiceAccounts = []string{"default", "pipeline"}
+
+// ImageRegistry represents the image registry integration coordinates. Works with
+// different TSSC integrations managing image registry configuration.
+type ImageRegistry struct {
+	logger *slog.Logger // application logger
+	kube   *k8s.Kube    // kubernetes client
+
+	force bool // overwrite the existing secret
+
+	dockerConfig   string // registry credentials (JSON)
+	dockerConfigRO string // registry read-only credentials (JSON)
+	url            string // API endpoint
+	token          string // API token
+
+	insecure          bool   // skips TLS verification when probing the registry
+	caCert            string // CA certificate bundle, path or inline PEM
+	skipRegistryProbe bool   // skips the live registry credentials probe
+
+	pullSecretServiceAccounts []string // extra "namespace/service-account" pairs to attach the secret to
+	pullSecretAllNamespaces   bool     // back-fill the secret into namespaces created during install
+}
+
+var _ Interface = &ImageRegistry{}
+
+const dockerConfigEx = `{ "auths": { "registry.tld": { "auth": "username" } } }`
+
+// PersistentFlags adds the persistent flags to the informed Cobra command.
+func (i *ImageRegistry) PersistentFlags(cmd *cobra.Command) {
+	p := cmd.PersistentFlags()
+
+	p.StringVar(&i.dockerConfig, "dockerconfigjson", i.dockerConfig,
+		fmt.Sprintf("JSON formatted registry credentials, e.g.: %q",
+			dockerConfigEx))
+	p.StringVar(
+		&i.dockerConfigRO, "dockerconfigjsonreadonly", i.dockerConfigRO,
+		fmt.Sprintf("JSON formatted read-only registry credentials, e.g.: %q",
+			dockerConfigEx))
+	p.StringVar(&i.url, "url", i.url, "Container registry API endpoint.")
+	p.StringVar(&i.token, "token", i.token, "Container registry API token.")
+
+	p.BoolVar(&i.insecure, "insecure", i.insecure,
+		"Skips TLS verification when probing the registry.")
+	p.StringVar(&i.caCert, "ca-cert", i.caCert,
+		"CA certificate bundle used to verify the registry, path or inline PEM.")
+	p.BoolVar(&i.skipRegistryProbe, "skip-registry-probe", i.skipRegistryProbe,
+		"Skips the live connection probe of the registry credentials.")
+
+	p.BoolVar(&i.force, "force", i.force,
+		"Overwrite the existing secret.")
+
+	p.StringSliceVar(
+		&i.pullSecretServiceAccounts, "pull-secret-service-accounts",
+		i.pullSecretServiceAccounts,
+		"Comma-separated 'namespace/service-account' pairs to additionally"+
+			" attach the registry secret to.")
+	p.BoolVar(
+		&i.pullSecretAllNamespaces, "pull-secret-all-namespaces",
+		i.pullSecretAllNamespaces,
+		"Watches namespace creation events during the install and back-fills"+
+			" the registry secret into each new namespace's default"+
+			" ServiceAccounts.")
+
+	for _, f := range []string{"dockerconfigjson", "url"} {
+		if err := cmd.MarkPersistentFlagRequired(f); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// SetArgument sets additional arguments to the integration.
+func (i *ImageRegistry) SetArgument(_, _ string) error {
+	return nil
+}
+
+// LoggerWith decorates the logger with the integration flags.
+func (i *ImageRegistry) LoggerWith(logger *slog.Logger) *slog.Logger {
+	return logger.With(
+		"dockerconfigjson-len", len(i.dockerConfig),
+		"dockerconfigjsonreadonly-len", len(i.dockerConfigRO),
+		"url", i.url,
+		"token-len", len(i.token),
+		"force", i.force,
+		"insecure", i.insecure,
+		"caCert-len", len(i.caCert),
+		"skipRegistryProbe", i.skipRegistryProbe,
+		"pullSecretServiceAccounts", i.pullSecretServiceAccounts,
+		"pullSecretAllNamespaces", i.pullSecretAllNamespaces,
+	)
+}
+
+// log returns the logger decorated with the integration flags.
+func (i *ImageRegistry) log() *slog.Logger {
+	return i.LoggerWith(i.logger)
+}
+
+// secretName returns the secret name for the integration. The name is "lazy"
+// generated to make sure configuration is already loaded.
+func (i *ImageRegistry) secretName(cfg *config.Config) types.NamespacedName {
+	return types.NamespacedName{
+		Namespace: cfg.Installer.Namespace,
+		Name:      "tssc-image-registry-integration",
+	}
+}
+
+// Validate validates the integration configuration, and, unless
+// --skip-registry-probe is set, connects to every registry referenced by the
+// credentials to confirm they are actually usable.
+func (i *ImageRegistry) Validate() error {
+	err := ValidateJSON("dockerconfigjson", i.dockerConfig)
+	if err != nil {
+		return err
+	}
+
+	if i.dockerConfigRO != "" {
+		err = ValidateJSON("dockerconfigjsonreadonly", i.dockerConfigRO)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err = ValidateURL(i.url); err != nil {
+		return err
+	}
+
+	if i.skipRegistryProbe {
+		return nil
+	}
+	return i.probeRegistries()
+}
+
+// dockerConfigJSON is the minimal shape of a ".dockerconfigjson" payload
+// needed to discover the registries and credentials to probe.
+type dockerConfigJSON struct {
+	Auths map[string]dockerConfigAuth `json:"auths"`
+}
+
+// dockerConfigAuth is a single entry of the "auths" map.
+type dockerConfigAuth struct {
+	Auth     string `json:"auth,omitempty"`
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+}
+
+// credentials returns the username/password pair for this auth entry,
+// decoding the base64 "user:pass" form when no explicit fields are set.
+func (a dockerConfigAuth) credentials() (string, string, error) {
+	if a.Username != "" || a.Password != "" {
+		return a.Username, a.Password, nil
+	}
+	if a.Auth == "" {
+		return "", "", fmt.Errorf("missing auth credentials")
+	}
+	decoded, err := base64.StdEncoding.DecodeString(a.Auth)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid auth encoding: %w", err)
+	}
+	username, password, ok := strings.Cut(string(decoded), ":")
+	if !ok {
+		return "", "", fmt.Errorf("invalid auth format")
+	}
+	return username, password, nil
+}
+
+// probeRegistries connects to every registry referenced by the configured
+// credentials, aggregating every failure so users see all misconfigurations
+// at once instead of discovering them one at a time.
+func (i *ImageRegistry) probeRegistries() error {
+	var errs []error
+
+	if err := i.probeDockerConfig("dockerconfigjson", i.dockerConfig); err != nil {
+		errs = append(errs, err)
+	}
+	if i.dockerConfigRO != "" {
+		if err := i.probeDockerConfig("dockerconfigjsonreadonly", i.dockerConfigRO); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if i.token != "" {
+		if err := i.probeToken(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// probeDockerConfig probes every registry referenced by the "auths" map of
+// the given dockerconfigjson payload.
+func (i *ImageRegistry) probeDockerConfig(field, raw string) error {
+	var cfg dockerConfigJSON
+	if err := json.Unmarshal([]byte(raw), &cfg); err != nil {
+		return fmt.Errorf("%s: %w", field, err)
+	}
+
+	var errs []error
+	for host, auth := range cfg.Auths {
+		username, password, err := auth.credentials()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s[%s]: %w", field, host, err))
+			continue
+		}
+		if err := i.probeHost(registryHost(host), username, password); err != nil {
+			errs = append(errs, fmt.Errorf("%s[%s]: %w", field, host, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// registryHost normalizes an "auths" map key into a bare host[:port]. Some
+// clients key it by a full URL instead of a bare host - notably Docker Hub's
+// "https://index.docker.io/v1/", produced by a plain "docker login" - which
+// would otherwise get a scheme and path prepended a second time when building
+// the probe endpoint.
+func registryHost(key string) string {
+	if !strings.Contains(key, "://") {
+		return key
+	}
+	u, err := url.Parse(key)
+	if err != nil || u.Host == "" {
+		return key
+	}
+	return u.Host
+}
+
+// probeHost performs a v2 discovery request against the registry host,
+// exchanging the basic-auth credentials for a bearer token when the registry
+// challenges the anonymous request.
+func (i *ImageRegistry) probeHost(host, username, password string) error {
+	client, err := i.httpClient()
+	if err != nil {
+		return err
+	}
+
+	endpoint := fmt.Sprintf("https://%s/v2/", host)
+	resp, err := client.Get(endpoint)
+	if err != nil {
+		return fmt.Errorf("failed to reach registry: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusUnauthorized:
+		token, err := i.exchangeToken(
+			client, resp.Header.Get("Www-Authenticate"), username, password)
+		if err != nil {
+			return err
+		}
+		return i.probeWithToken(client, endpoint, token)
+	default:
+		return fmt.Errorf("unexpected status %d from %s", resp.StatusCode, endpoint)
+	}
+}
+
+// exchangeToken follows a "Www-Authenticate: Bearer realm=...,service=..."
+// challenge, exchanging the basic-auth credentials for a bearer token.
+func (i *ImageRegistry) exchangeToken(
+	client *http.Client, challenge, username, password string,
+) (string, error) {
+	realm, service, err := parseBearerChallenge(challenge)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequest(http.MethodGet, realm, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to build token request: %w", err)
+	}
+	if service != "" {
+		q := req.URL.Query()
+		q.Set("service", service)
+		req.URL.RawQuery = q.Encode()
+	}
+	if username != "" || password != "" {
+		req.SetBasicAuth(username, password)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to exchange token: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("token exchange returned status %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("failed to decode token response: %w", err)
+	}
+	switch {
+	case body.Token != "":
+		return body.Token, nil
+	case body.AccessToken != "":
+		return body.AccessToken, nil
+	default:
+		return "", fmt.Errorf("token exchange response is missing a token")
+	}
+}
+
+// parseBearerChallenge extracts the realm and service from a
+// "Bearer realm=\"...\",service=\"...\"" Www-Authenticate header.
+func parseBearerChallenge(challenge string) (realm, service string, err error) {
+	if !strings.HasPrefix(challenge, "Bearer ") {
+		return "", "", fmt.Errorf("unsupported auth challenge: %q", challenge)
+	}
+
+	params := map[string]string{}
+	for _, part := range strings.Split(strings.TrimPrefix(challenge, "Bearer "), ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		params[key] = strings.Trim(value, `"`)
+	}
+
+	realm, ok := params["realm"]
+	if !ok {
+		return "", "", fmt.Errorf("auth challenge is missing a realm: %q", challenge)
+	}
+	return realm, params["service"], nil
+}
+
+// probeWithToken re-requests the v2 endpoint with the exchanged bearer token
+// to confirm the credentials are actually accepted by the registry.
+func (i *ImageRegistry) probeWithToken(client *http.Client, endpoint, token string) error {
+	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to build authenticated request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to re-check registry: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf(
+			"registry rejected the exchanged token with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// probeToken validates the standalone "token" credential against the
+// registry API endpoint.
+func (i *ImageRegistry) probeToken() error {
+	client, err := i.httpClient()
+	if err != nil {
+		return fmt.Errorf("token: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, i.url, nil)
+	if err != nil {
+		return fmt.Errorf("token: failed to build request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+i.token)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("token: failed to reach %s: %w", i.url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusUnauthorized {
+		return fmt.Errorf("token: %s rejected the token", i.url)
+	}
+	return nil
+}
+
+// httpClient builds the *http.Client used to probe registries, honoring
+// --insecure and --ca-cert so air-gapped registries behind a private CA work.
+// It reuses tlsConfigFromPEM, the same TLS construction GitLabIntegration
+// uses, rather than re-implementing it.
+func (i *ImageRegistry) httpClient() (*http.Client, error) {
+	tlsConfig, err := tlsConfigFromPEM(i.caCert, "", "", i.insecure)
+	if err != nil {
+		return nil, err
+	}
+	return &http.Client{
+		Timeout:   registryProbeTimeout,
+		Transport: &http.Transport{TLSClientConfig: tlsConfig},
+	}, nil
+}
+
+// Type returns the type of the integration.
+func (i *ImageRegistry) Type() corev1.SecretType {
+	return corev1.SecretTypeDockerConfigJson
+}
+
+// Data returns the integration data.
+func (i *ImageRegistry) Data(
+	_ context.Context,
+	_ *config.Config,
+) (map[string][]byte, error) {
+	return map[string][]byte{
+		".dockerconfigjson":         []byte(i.dockerConfig),
+		".dockerconfigjsonreadonly": []byte(i.dockerConfigRO),
+		"url":                       []byte(i.url),
+		"token":                     []byte(i.token),
+	}, nil
+}
+
+// EnsurePullSecrets patches the imagePullSecrets of the default
+// ServiceAccounts (see defaultImagePullServiceAccounts) in cfg.Installer.Namespace,
+// plus any additional "namespace/service-account" pairs informed via
+// --pull-secret-service-accounts, so pods launched by TSSC components can pull
+// from this registry out of the box. Create calls it once secretName exists
+// in the cluster.
+func (i *ImageRegistry) EnsurePullSecrets(
+	ctx context.Context,
+	kube *k8s.Kube,
+	cfg *config.Config,
+	secretName string,
+) error {
+	var errs []error
+
+	for _, sa := range defaultImagePullServiceAccounts {
+		if err := k8s.EnsureImagePullSecret(
+			ctx, kube, cfg.Installer.Namespace, sa, secretName,
+		); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	for _, pair := range i.pullSecretServiceAccounts {
+		namespace, sa, ok := strings.Cut(pair, "/")
+		if !ok {
+			errs = append(errs, fmt.Errorf(
+				"invalid --pull-secret-service-accounts entry %q,"+
+					" expected 'namespace/service-account'", pair))
+			continue
+		}
+		if err := k8s.EnsureImagePullSecret(
+			ctx, kube, namespace, sa, secretName,
+		); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// prepareSecret checks if the secret already exists, and if so, it will
+// delete the secret if the force flag is enabled, mirroring
+// GitLabIntegration.prepareSecret.
+func (i *ImageRegistry) prepareSecret(ctx context.Context, cfg *config.Config) error {
+	logger := i.log()
+	logger.Debug("Checking if integration secret exists")
+	exists, err := k8s.SecretExists(ctx, i.kube, i.secretName(cfg))
+	if err != nil {
+		return err
+	}
+	if !exists {
+		logger.Debug("Integration secret does not exist")
+		return nil
+	}
+	if !i.force {
+		logger.Debug("Integration secret already exists")
+		return fmt.Errorf("%w: %s",
+			ErrSecretAlreadyExists, i.secretName(cfg).String())
+	}
+	logger.Debug("Integration secret already exists, recreating it")
+	return k8s.DeleteSecret(ctx, i.kube, i.secretName(cfg))
+}
+
+// Create creates the image registry integration Kubernetes secret, then
+// attaches it to the ServiceAccounts EnsurePullSecrets targets so pods can
+// pull from this registry right away. With --pull-secret-all-namespaces it
+// also starts watchNamespacesForPullSecret in the background for the
+// duration of the install.
+func (i *ImageRegistry) Create(
+	ctx context.Context,
+	cfg *config.Config,
+) error {
+	logger := i.log()
+	logger.Info("Inspecting the cluster for an existing image registry integration secret")
+	if err := i.prepareSecret(ctx, cfg); err != nil {
+		return err
+	}
+
+	data, err := i.Data(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: i.secretName(cfg).Namespace,
+			Name:      i.secretName(cfg).Name,
+		},
+		Type: i.Type(),
+		Data: data,
+	}
+
+	coreClient, err := i.kube.CoreV1ClientSet(i.secretName(cfg).Namespace)
+	if err != nil {
+		return err
+	}
+	if _, err := coreClient.Secrets(i.secretName(cfg).Namespace).
+		Create(ctx, secret, metav1.CreateOptions{}); err != nil {
+		return fmt.Errorf("failed to create integration secret: %w", err)
+	}
+	logger.Info("Integration secret created successfully!")
+
+	if i.pullSecretAllNamespaces {
+		go i.watchNamespacesForPullSecret(ctx, i.secretName(cfg).Name)
+	}
+
+	return i.EnsurePullSecrets(ctx, i.kube, cfg, i.secretName(cfg).Name)
+}
+
+// watchNamespacesForPullSecret watches for Namespace creation events and
+// attaches secretName to each new namespace's default ServiceAccounts (see
+// defaultImagePullServiceAccounts), so namespaces created during the install
+// after this integration's secret already exists still receive the registry
+// credentials. It runs until ctx is done, logging and continuing on a
+// per-namespace failure rather than aborting the watch. Wiring the same
+// back-fill into the deploy Job for namespaces created after install is left
+// to pkg/installer, which is not part of this package.
+func (i *ImageRegistry) watchNamespacesForPullSecret(ctx context.Context, secretName string) {
+	logger := i.log()
+	coreClient, err := i.kube.CoreV1ClientSet("")
+	if err != nil {
+		logger.Error("Failed to start namespace watch for pull secrets", "error", err)
+		return
+	}
+
+	watcher, err := coreClient.Namespaces().Watch(ctx, metav1.ListOptions{})
+	if err != nil {
+		logger.Error("Failed to watch namespaces for pull secrets", "error", err)
+		return
+	}
+	defer watcher.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-watcher.ResultChan():
+			if !ok {
+				return
+			}
+			if event.Type != watch.Added {
+				continue
+			}
+			ns, ok := event.Object.(*corev1.Namespace)
+			if !ok {
+				continue
+			}
+			for _, sa := range defaultImagePullServiceAccounts {
+				if err := k8s.EnsureImagePullSecret(
+					ctx, i.kube, ns.Name, sa, secretName,
+				); err != nil {
+					logger.Error("Failed to attach image pull secret to new namespace",
+						"namespace", ns.Name, "service-account", sa, "error", err)
+				}
+			}
+		}
+	}
+}
+
+// WithKube sets the Kubernetes client used by Create (which hands it to
+// EnsurePullSecrets) and by the namespace watch, without breaking
+// NewContainerRegistry's existing signature. The registry probe does not use
+// it.
+func (i *ImageRegistry) WithKube(kube *k8s.Kube) *ImageRegistry {
+	i.kube = kube
+	return i
+}
+
+// WithLogger sets the application logger used by Create.
+func (i *ImageRegistry) WithLogger(logger *slog.Logger) *ImageRegistry {
+	i.logger = logger
+	return i
+}
+
+// NewContainerRegistry creates a new instance with the default URL.
+func NewContainerRegistry(defaultURL string) *ImageRegistry {
+	return &ImageRegistry{url: defaultURL}
+}
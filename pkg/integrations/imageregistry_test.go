@@ -0,0 +1,148 @@
+package integrations
+
+import "testing"
+
+func TestDockerConfigAuthCredentials(t *testing.T) {
+	tests := []struct {
+		name         string
+		auth         dockerConfigAuth
+		wantUsername string
+		wantPassword string
+		wantErr      bool
+	}{
+		{
+			name:         "explicit username and password",
+			auth:         dockerConfigAuth{Username: "alice", Password: "s3cr3t"},
+			wantUsername: "alice",
+			wantPassword: "s3cr3t",
+		},
+		{
+			name:         "base64 user:pass auth field",
+			auth:         dockerConfigAuth{Auth: "YWxpY2U6czNjcjN0"}, // alice:s3cr3t
+			wantUsername: "alice",
+			wantPassword: "s3cr3t",
+		},
+		{
+			name:    "empty",
+			auth:    dockerConfigAuth{},
+			wantErr: true,
+		},
+		{
+			name:    "invalid base64",
+			auth:    dockerConfigAuth{Auth: "not-base64!!"},
+			wantErr: true,
+		},
+		{
+			name:    "base64 without a colon separator",
+			auth:    dockerConfigAuth{Auth: "YWxpY2U="}, // "alice"
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			username, password, err := tt.auth.credentials()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("credentials() = %q, %q, nil; want error", username, password)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("credentials() unexpected error: %v", err)
+			}
+			if username != tt.wantUsername || password != tt.wantPassword {
+				t.Errorf("credentials() = %q, %q; want %q, %q",
+					username, password, tt.wantUsername, tt.wantPassword)
+			}
+		})
+	}
+}
+
+func TestRegistryHost(t *testing.T) {
+	tests := []struct {
+		name string
+		key  string
+		want string
+	}{
+		{
+			name: "bare host",
+			key:  "quay.io",
+			want: "quay.io",
+		},
+		{
+			name: "bare host with port",
+			key:  "registry.example.com:5000",
+			want: "registry.example.com:5000",
+		},
+		{
+			name: "docker hub's full URL key from plain docker login",
+			key:  "https://index.docker.io/v1/",
+			want: "index.docker.io",
+		},
+		{
+			name: "unparseable key is returned unchanged",
+			key:  "://not-a-url",
+			want: "://not-a-url",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := registryHost(tt.key); got != tt.want {
+				t.Errorf("registryHost(%q) = %q; want %q", tt.key, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseBearerChallenge(t *testing.T) {
+	tests := []struct {
+		name        string
+		challenge   string
+		wantRealm   string
+		wantService string
+		wantErr     bool
+	}{
+		{
+			name:        "realm and service",
+			challenge:   `Bearer realm="https://auth.docker.io/token",service="registry.docker.io"`,
+			wantRealm:   "https://auth.docker.io/token",
+			wantService: "registry.docker.io",
+		},
+		{
+			name:      "realm only",
+			challenge: `Bearer realm="https://quay.io/v2/auth"`,
+			wantRealm: "https://quay.io/v2/auth",
+		},
+		{
+			name:      "missing realm",
+			challenge: `Bearer service="registry.docker.io"`,
+			wantErr:   true,
+		},
+		{
+			name:      "not a bearer challenge",
+			challenge: `Basic realm="registry"`,
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			realm, service, err := parseBearerChallenge(tt.challenge)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseBearerChallenge(%q) = %q, %q, nil; want error", tt.challenge, realm, service)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseBearerChallenge(%q) unexpected error: %v", tt.challenge, err)
+			}
+			if realm != tt.wantRealm || service != tt.wantService {
+				t.Errorf("parseBearerChallenge(%q) = %q, %q; want %q, %q",
+					tt.challenge, realm, service, tt.wantRealm, tt.wantService)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// EnsureImagePullSecret makes sure secretName is referenced by the
+// imagePullSecrets of the named ServiceAccount, creating the ServiceAccount
+// when it does not exist yet. The secret reference is appended idempotently,
+// so calling this repeatedly for the same namespace/serviceAccount/secretName
+// is safe.
+func EnsureImagePullSecret(
+	ctx context.Context,
+	kube *Kube,
+	namespace string,
+	serviceAccount string,
+	secretName string,
+) error {
+	coreClient, err := kube.CoreV1ClientSet(namespace)
+	if err != nil {
+		return err
+	}
+	saClient := coreClient.ServiceAccounts(namespace)
+
+	sa, err := saClient.Get(ctx, serviceAccount, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		sa, err = saClient.Create(ctx, &corev1.ServiceAccount{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace,
+				Name:      serviceAccount,
+			},
+		}, metav1.CreateOptions{})
+	}
+	if err != nil {
+		return fmt.Errorf(
+			"failed to get/create service account %q/%q: %w",
+			namespace, serviceAccount, err)
+	}
+
+	for _, ref := range sa.ImagePullSecrets {
+		if ref.Name == secretName {
+			return nil
+		}
+	}
+	sa.ImagePullSecrets = append(
+		sa.ImagePullSecrets, corev1.LocalObjectReference{Name: secretName})
+
+	if _, err := saClient.Update(ctx, sa, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf(
+			"failed to attach image pull secret %q to %q/%q: %w",
+			secretName, namespace, serviceAccount, err)
+	}
+	return nil
+}
@@ -2,26 +2,56 @@ package mcptools
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"log/slog"
 	"strings"
+	"sync"
 
 	"github.com/redhat-appstudio/tssc-cli/pkg/config"
 	"github.com/redhat-appstudio/tssc-cli/pkg/installer"
+	"github.com/redhat-appstudio/tssc-cli/pkg/k8s"
 	"github.com/redhat-appstudio/tssc-cli/pkg/resolver"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/release"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// notableEventLimit bounds how many notable pod events are surfaced per
+// non-deployed release in the "tssc_status" inventory.
+const notableEventLimit = 5
+
 // DeployTools represents the tools used for deploying the TSSC using the
 // installer on a container image, and running in the cluster, using a Kubernetes
 // Job.
 type DeployTools struct {
+	logger          *slog.Logger              // application logger
 	cm              *config.ConfigMapManager  // cluster configuration
+	kube            *k8s.Kube                 // kubernetes client
 	topologyBuilder *resolver.TopologyBuilder // topology builder
 	job             *installer.Job            // cluster deployment job
 	image           string                    // tssc container image
+
+	helmActionConfigsMu sync.Mutex
+	helmActionConfigs   map[string]*action.Configuration // cached per namespace
+}
+
+// releaseStatus is one entry of the "tssc_status" Helm release inventory.
+type releaseStatus struct {
+	Name         string   `json:"name"`
+	Namespace    string   `json:"namespace"`
+	Chart        string   `json:"chart,omitempty"`
+	ChartVersion string   `json:"chartVersion,omitempty"`
+	AppVersion   string   `json:"appVersion,omitempty"`
+	Revision     int      `json:"revision,omitempty"`
+	Status       string   `json:"status"`
+	LastDeployed string   `json:"lastDeployed,omitempty"`
+	Events       []string `json:"events,omitempty"`
 }
 
 // statusHandler handles the status of the deployment job. It checks if the
@@ -142,6 +172,223 @@ product deployed:
 	return nil, fmt.Errorf("unknown deployment state %q", state)
 }
 
+// statusInventoryHandler handles the Helm release inventory for the TSSC
+// components declared in the resolved topology. See RHTAP-4826.
+func (d *DeployTools) statusInventoryHandler(
+	ctx context.Context,
+	ctr mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	cfg, err := d.cm.GetConfig(ctx)
+	if err != nil {
+		return mcp.NewToolResultText(fmt.Sprintf(`
+The cluster is not configured yet, use the tool 'tssc_config_create' to configure
+it before inspecting the Helm release inventory.
+
+Inspecting the configuration in the cluster returned the following error:
+
+%s`,
+			err.Error(),
+		)), nil
+	}
+
+	topology, err := d.topologyBuilder.Build(ctx, cfg)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	statuses, err := d.collectReleaseStatuses(ctx, topology)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := json.MarshalIndent(statuses, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode release inventory: %w", err)
+	}
+	return mcp.NewToolResultText(string(payload)), nil
+}
+
+// collectReleaseStatuses reconciles the Helm releases found in each
+// topology-declared chart's namespace against the topology itself: charts
+// expected but missing are reported as "not-installed", releases present but
+// not part of the topology are flagged as "orphan".
+func (d *DeployTools) collectReleaseStatuses(
+	ctx context.Context,
+	topology *resolver.Topology,
+) ([]releaseStatus, error) {
+	byNamespace := map[string][]resolver.Chart{}
+	expected := map[string]map[string]bool{}
+	for _, chart := range topology.Charts() {
+		byNamespace[chart.Namespace] = append(byNamespace[chart.Namespace], chart)
+		if expected[chart.Namespace] == nil {
+			expected[chart.Namespace] = map[string]bool{}
+		}
+		expected[chart.Namespace][chart.Name] = true
+	}
+
+	statuses := []releaseStatus{}
+	for namespace, charts := range byNamespace {
+		releases, err := d.listHelmReleases(namespace)
+		if err != nil {
+			return nil, err
+		}
+
+		releaseByName := map[string]*release.Release{}
+		for _, rel := range releases {
+			releaseByName[rel.Name] = rel
+		}
+
+		for _, chart := range charts {
+			rel, ok := releaseByName[chart.Name]
+			if !ok {
+				statuses = append(statuses, releaseStatus{
+					Name:      chart.Name,
+					Namespace: namespace,
+					Status:    "not-installed",
+				})
+				continue
+			}
+			statuses = append(statuses, d.toReleaseStatus(ctx, namespace, rel, ""))
+		}
+
+		for _, rel := range releases {
+			if expected[namespace][rel.Name] {
+				continue
+			}
+			statuses = append(statuses, d.toReleaseStatus(ctx, namespace, rel, "orphan"))
+		}
+	}
+
+	return statuses, nil
+}
+
+// toReleaseStatus converts a Helm release into its "tssc_status" entry,
+// overriding the reported status with forcedStatus when informed (used to
+// flag orphan releases), and collecting notable pod events when the release
+// is not in the "deployed" state.
+func (d *DeployTools) toReleaseStatus(
+	ctx context.Context,
+	namespace string,
+	rel *release.Release,
+	forcedStatus string,
+) releaseStatus {
+	status := releaseStatus{
+		Name:         rel.Name,
+		Namespace:    namespace,
+		Chart:        rel.Chart.Metadata.Name,
+		ChartVersion: rel.Chart.Metadata.Version,
+		AppVersion:   rel.Chart.Metadata.AppVersion,
+		Revision:     rel.Version,
+		Status:       rel.Info.Status.String(),
+		LastDeployed: rel.Info.LastDeployed.String(),
+	}
+	if forcedStatus != "" {
+		status.Status = forcedStatus
+	}
+
+	if rel.Info.Status != release.StatusDeployed {
+		events, err := d.releaseEvents(ctx, namespace, rel.Name)
+		if err != nil {
+			d.logger.Warn("Failed to collect release events",
+				"release", rel.Name, "namespace", namespace, "error", err)
+		} else {
+			status.Events = events
+		}
+	}
+	return status
+}
+
+// listHelmReleases lists every Helm release in namespace, using the cached
+// action.Configuration for that namespace.
+func (d *DeployTools) listHelmReleases(namespace string) ([]*release.Release, error) {
+	actionConfig, err := d.helmActionConfig(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	list := action.NewList(actionConfig)
+	list.All = true
+	releases, err := list.Run()
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to list helm releases in namespace %q: %w", namespace, err)
+	}
+	return releases, nil
+}
+
+// helmActionConfig returns the Helm action.Configuration scoped to namespace,
+// caching it so repeated "tssc_status" calls do not rebuild the REST mapper
+// every time.
+func (d *DeployTools) helmActionConfig(namespace string) (*action.Configuration, error) {
+	d.helmActionConfigsMu.Lock()
+	defer d.helmActionConfigsMu.Unlock()
+
+	if actionConfig, ok := d.helmActionConfigs[namespace]; ok {
+		return actionConfig, nil
+	}
+
+	actionConfig := new(action.Configuration)
+	err := actionConfig.Init(
+		d.kube.RESTClientGetter(namespace),
+		namespace,
+		"secrets",
+		func(format string, v ...interface{}) {
+			d.logger.Debug(fmt.Sprintf(format, v...))
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to initialize helm action config for namespace %q: %w",
+			namespace, err)
+	}
+
+	d.helmActionConfigs[namespace] = actionConfig
+	return actionConfig, nil
+}
+
+// releaseEvents collects the last few notable (non-Normal) events from pods
+// owned by release in namespace, using the
+// "app.kubernetes.io/instance=<release>" label selector.
+func (d *DeployTools) releaseEvents(
+	ctx context.Context,
+	namespace string,
+	release string,
+) ([]string, error) {
+	coreClient, err := d.kube.CoreV1ClientSet(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	pods, err := coreClient.Pods(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("app.kubernetes.io/instance=%s", release),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var notable []string
+	for _, pod := range pods.Items {
+		events, err := coreClient.Events(namespace).List(ctx, metav1.ListOptions{
+			FieldSelector: fmt.Sprintf("involvedObject.name=%s", pod.Name),
+		})
+		if err != nil {
+			continue
+		}
+		for _, event := range events.Items {
+			if event.Type == corev1.EventTypeNormal {
+				continue
+			}
+			notable = append(notable, fmt.Sprintf(
+				"%s/%s: %s", pod.Name, event.Reason, event.Message))
+		}
+	}
+
+	if len(notable) > notableEventLimit {
+		notable = notable[len(notable)-notableEventLimit:]
+	}
+	return notable, nil
+}
+
 // deployHandler handles the deployment of TSSC components.
 func (d *DeployTools) deployHandler(
 	ctx context.Context,
@@ -177,10 +424,6 @@ You can follow the logs by running:
 // Init registers the deployment tools on the MCP server.
 func (d *DeployTools) Init(s *server.MCPServer) {
 	s.AddTools([]server.ServerTool{{
-		// TODO: the installer status will be moved to a dedicated function,
-		// "tssc_status", see RHTAP-4826 for more details. While this MCP function
-		// only shows the deploy job status, the future "tssc_status" will include
-		// the installed Helm charts and more.
 		Tool: mcp.NewTool(
 			"tssc_deploy_status",
 			mcp.WithDescription(`
@@ -196,14 +439,34 @@ the TSSC components sequentially.`,
 			),
 		),
 		Handler: d.deployHandler,
+	}, {
+		Tool: mcp.NewTool(
+			"tssc_status",
+			mcp.WithDescription(`
+Reports the Helm release inventory for the TSSC components declared in the
+resolved topology: per-release chart version, app version, revision and
+status, flagging charts that are expected but not installed, and releases
+that are installed but not part of the topology.`,
+			),
+		),
+		Handler: d.statusInventoryHandler,
 	}}...)
 }
 
-// NewDeployTools creates a new DeployTools instance.l
+// NewDeployTools creates a new DeployTools instance.
 func NewDeployTools(
+	logger *slog.Logger, // application logger
 	cm *config.ConfigMapManager, // cluster configuration manager
+	kube *k8s.Kube, // kubernetes client
 	job *installer.Job, // job manager instance
 	image string, // container image
 ) *DeployTools {
-	return &DeployTools{cm: cm, job: job, image: image}
+	return &DeployTools{
+		logger:            logger,
+		cm:                cm,
+		kube:              kube,
+		job:               job,
+		image:             image,
+		helmActionConfigs: map[string]*action.Configuration{},
+	}
 }
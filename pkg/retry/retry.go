@@ -0,0 +1,156 @@
+// Package retry provides an exponential-backoff-with-jitter helper for the
+// transient failures integrations run into when talking to the GitLab and
+// Kubernetes APIs (connection resets, rate limiting, brief outages), so
+// callers don't have to hand-roll a retry loop around every API call.
+package retry
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// Config configures the exponential backoff used by Do.
+type Config struct {
+	MinWait     time.Duration // backoff before the first retry
+	MaxWait     time.Duration // backoff ceiling
+	Factor      float64       // exponential growth factor applied between retries
+	MaxAttempts int           // maximum number of attempts, including the first
+}
+
+// DefaultConfig makes up to 5 attempts, starting at 500ms and doubling up to a
+// 30s ceiling, modeled on the jpillora/backoff pattern used by the GitLab
+// Runner Kubernetes executor.
+var DefaultConfig = Config{
+	MinWait:     500 * time.Millisecond,
+	MaxWait:     30 * time.Second,
+	Factor:      2,
+	MaxAttempts: 5,
+}
+
+// Func is the operation retried by Do.
+type Func func(ctx context.Context) error
+
+// Do runs fn, retrying with exponential backoff and jitter while Retryable(err)
+// holds and attempts remain. It stops early if ctx is done. fn always runs at
+// least once, even if cfg.MaxAttempts is 0 or negative, so a misconfigured
+// Config can never skip the call it's supposed to guard.
+func Do(ctx context.Context, cfg Config, fn Func) error {
+	wait := cfg.MinWait
+	maxAttempts := cfg.MaxAttempts
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	var err error
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		if err = fn(ctx); err == nil {
+			return nil
+		}
+		if attempt == maxAttempts || !Retryable(err) {
+			return err
+		}
+
+		delay := wait
+		var afterErr *afterError
+		if errors.As(err, &afterErr) {
+			delay = afterErr.after
+		} else {
+			wait = time.Duration(math.Min(float64(cfg.MaxWait), float64(wait)*cfg.Factor))
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(jitter(delay)):
+		}
+	}
+	return err
+}
+
+// jitter returns a random duration in [d/2, d), smoothing out retry bursts
+// from multiple callers backing off in lockstep.
+func jitter(d time.Duration) time.Duration {
+	if d <= 0 {
+		return 0
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d-d/2)))
+}
+
+// afterError overrides the computed backoff with a server-provided wait,
+// e.g. an HTTP "Retry-After" header.
+type afterError struct {
+	err   error
+	after time.Duration
+}
+
+func (e *afterError) Error() string { return e.err.Error() }
+func (e *afterError) Unwrap() error { return e.err }
+
+// After wraps err so the next Do retry waits for "after" instead of the
+// computed backoff delay. Use it when the failing API informed how long to
+// wait (e.g. GitLab's "Retry-After" header).
+func After(err error, after time.Duration) error {
+	if err == nil {
+		return nil
+	}
+	return &afterError{err: err, after: after}
+}
+
+// HTTPStatusError wraps a non-2xx HTTP response so Retryable can classify it
+// by status code.
+type HTTPStatusError struct {
+	StatusCode int
+	Status     string
+}
+
+func (e *HTTPStatusError) Error() string { return e.Status }
+
+// Retryable classifies err as transient: net.OpError, context.DeadlineExceeded,
+// HTTP 429/502/503/504, and the Kubernetes server-timeout/too-many-requests/
+// internal-error conditions. Other 4xx client errors and "already exists" are
+// never retried.
+func Retryable(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var afterErr *afterError
+	if errors.As(err, &afterErr) {
+		err = afterErr.err
+	}
+
+	if apierrors.IsAlreadyExists(err) {
+		return false
+	}
+	if apierrors.IsServerTimeout(err) ||
+		apierrors.IsTooManyRequests(err) ||
+		apierrors.IsInternalError(err) {
+		return true
+	}
+
+	var netErr *net.OpError
+	if errors.As(err, &netErr) {
+		return true
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var statusErr *HTTPStatusError
+	if errors.As(err, &statusErr) {
+		switch statusErr.StatusCode {
+		case http.StatusTooManyRequests, http.StatusBadGateway,
+			http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+	}
+
+	return false
+}
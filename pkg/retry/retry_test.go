@@ -0,0 +1,195 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestDoRunsAtLeastOnce(t *testing.T) {
+	tests := []struct {
+		name        string
+		maxAttempts int
+	}{
+		{name: "zero MaxAttempts", maxAttempts: 0},
+		{name: "negative MaxAttempts", maxAttempts: -3},
+		{name: "positive MaxAttempts", maxAttempts: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			cfg := Config{MinWait: time.Millisecond, MaxWait: time.Millisecond, Factor: 2, MaxAttempts: tt.maxAttempts}
+			err := Do(context.Background(), cfg, func(ctx context.Context) error {
+				calls++
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if calls != 1 {
+				t.Errorf("fn called %d times; want exactly 1", calls)
+			}
+		})
+	}
+}
+
+func TestDoRetriesTransientFailures(t *testing.T) {
+	calls := 0
+	cfg := Config{MinWait: time.Millisecond, MaxWait: 2 * time.Millisecond, Factor: 2, MaxAttempts: 3}
+	retryableErr := &HTTPStatusError{StatusCode: 503, Status: "503 Service Unavailable"}
+
+	err := Do(context.Background(), cfg, func(ctx context.Context) error {
+		calls++
+		if calls < 3 {
+			return retryableErr
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("fn called %d times; want 3", calls)
+	}
+}
+
+func TestDoStopsOnNonRetryableError(t *testing.T) {
+	calls := 0
+	cfg := Config{MinWait: time.Millisecond, MaxWait: time.Millisecond, Factor: 2, MaxAttempts: 5}
+	nonRetryable := errors.New("permanent failure")
+
+	err := Do(context.Background(), cfg, func(ctx context.Context) error {
+		calls++
+		return nonRetryable
+	})
+	if !errors.Is(err, nonRetryable) {
+		t.Fatalf("err = %v; want %v", err, nonRetryable)
+	}
+	if calls != 1 {
+		t.Errorf("fn called %d times; want 1, since the error isn't retryable", calls)
+	}
+}
+
+func TestDoStopsWhenAttemptsExhausted(t *testing.T) {
+	calls := 0
+	cfg := Config{MinWait: time.Millisecond, MaxWait: time.Millisecond, Factor: 2, MaxAttempts: 3}
+	retryableErr := &HTTPStatusError{StatusCode: 429, Status: "429 Too Many Requests"}
+
+	err := Do(context.Background(), cfg, func(ctx context.Context) error {
+		calls++
+		return retryableErr
+	})
+	if !errors.Is(err, retryableErr) {
+		t.Fatalf("err = %v; want %v", err, retryableErr)
+	}
+	if calls != 3 {
+		t.Errorf("fn called %d times; want exactly MaxAttempts (3)", calls)
+	}
+}
+
+func TestDoRespectsContextCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cfg := Config{MinWait: time.Hour, MaxWait: time.Hour, Factor: 2, MaxAttempts: 5}
+	retryableErr := &HTTPStatusError{StatusCode: 503, Status: "503 Service Unavailable"}
+
+	calls := 0
+	done := make(chan error, 1)
+	go func() {
+		done <- Do(ctx, cfg, func(ctx context.Context) error {
+			calls++
+			return retryableErr
+		})
+	}()
+
+	cancel()
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("err = %v; want context.Canceled", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Do did not return after context cancellation")
+	}
+	if calls != 1 {
+		t.Errorf("fn called %d times; want exactly 1 before the cancellation was observed", calls)
+	}
+}
+
+func TestDoHonorsAfterError(t *testing.T) {
+	calls := 0
+	var firstDelay time.Duration
+	start := time.Now()
+	cfg := Config{MinWait: time.Hour, MaxWait: time.Hour, Factor: 2, MaxAttempts: 2}
+
+	err := Do(context.Background(), cfg, func(ctx context.Context) error {
+		calls++
+		if calls == 1 {
+			rateLimited := &HTTPStatusError{StatusCode: 429, Status: "429 Too Many Requests"}
+			return After(rateLimited, 10*time.Millisecond)
+		}
+		firstDelay = time.Since(start)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if firstDelay >= time.Hour {
+		t.Errorf("retry waited %v; After should have overridden the hour-long backoff", firstDelay)
+	}
+}
+
+func TestRetryable(t *testing.T) {
+	gr := schema.GroupResource{Group: "", Resource: "pods"}
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "nil", err: nil, want: false},
+		{name: "already exists", err: apierrors.NewAlreadyExists(gr, "name"), want: false},
+		{name: "server timeout", err: apierrors.NewServerTimeout(gr, "get", 0), want: true},
+		{name: "too many requests", err: apierrors.NewTooManyRequests("busy", 0), want: true},
+		{name: "internal error", err: apierrors.NewInternalError(errors.New("boom")), want: true},
+		{name: "context deadline exceeded", err: context.DeadlineExceeded, want: true},
+		{name: "http 503", err: &HTTPStatusError{StatusCode: 503}, want: true},
+		{name: "http 404", err: &HTTPStatusError{StatusCode: 404}, want: false},
+		{name: "plain error", err: errors.New("nope"), want: false},
+		{
+			name: "wrapped in afterError",
+			err:  After(&HTTPStatusError{StatusCode: 502}, time.Second),
+			want: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Retryable(tt.err); got != tt.want {
+				t.Errorf("Retryable(%v) = %v; want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAfterNilError(t *testing.T) {
+	if err := After(nil, time.Second); err != nil {
+		t.Errorf("After(nil, ...) = %v; want nil", err)
+	}
+}
+
+func TestJitterBounds(t *testing.T) {
+	d := 100 * time.Millisecond
+	for i := 0; i < 100; i++ {
+		got := jitter(d)
+		if got < d/2 || got >= d {
+			t.Fatalf("jitter(%v) = %v; want in [%v, %v)", d, got, d/2, d)
+		}
+	}
+	if got := jitter(0); got != 0 {
+		t.Errorf("jitter(0) = %v; want 0", got)
+	}
+}